@@ -1,16 +1,89 @@
 package errorhandling
 
 import (
-	"fmt"
+	"bytes"
+	"io"
+	"os"
 	"runtime/debug"
+	"sync"
 	"testing"
 )
 
 // set via -ldflags "-X github.com/FactomProject/electiontesting/errorhandling.ErrorMode=debug" on the build line
-var ErrorMode string // "" is production, "testing" is running a go test, "debug" is development
+var ErrorMode string // "" is production, "testing" is running a go test, "debug" is development, "collect" saves them
 var T *testing.T     // Should be set by all tests first
 
+// Output is where production mode prints to
+var Output io.Writer = os.Stdout
+
+// Level is the severity of a message passed to HandleLog
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// threshold is the lowest level that is reported, anything below is dropped. Fatal is never dropped
+var threshold Level = LevelDebug
+
+// SetThreshold changes the threshold, safe to call while nodes are reporting
+func SetThreshold(level Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	threshold = level
+}
+
+func GetThreshold() Level {
+	mu.Lock()
+	defer mu.Unlock()
+	return threshold
+}
+
+// CaptureErrors runs f with Output sent to a buffer and returns what was written to it
+func CaptureErrors(f func()) string {
+	buf := new(bytes.Buffer)
+	mu.Lock()
+	old := Output
+	Output = buf
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		Output = old
+		mu.Unlock()
+	}()
+
+	f()
+	return buf.String()
+}
+
+// In "collect" mode every note is saved here instead of printed, so a harness can check them at the end
+var collected struct {
+	sync.Mutex
+	notes []string
+}
+
+// CollectedErrors returns a copy of the notes saved in "collect" mode
+func CollectedErrors() []string {
+	collected.Lock()
+	defer collected.Unlock()
+	notes := make([]string, len(collected.notes))
+	copy(notes, collected.notes)
+	return notes
+}
+
+func ResetCollectedErrors() {
+	collected.Lock()
+	defer collected.Unlock()
+	collected.notes = nil
+}
+
 func StartUnitTestErrorHandling(t *testing.T) {
+	mu.Lock()
+	defer mu.Unlock()
 	T = t
 	ErrorMode = "testing"
 }
@@ -21,76 +94,46 @@ func ExpMsg(found bool) {
 	}
 }
 
+// HandleLog reports a message at the given level to the installed Logger, or to the
+// Logger for the ErrorMode if none is installed. In debug and testing mode only LevelError
+// and above panic or fail the test.
+func HandleLog(level Level, format string, a ...interface{}) {
+	l := currentLogger(level)
+	switch {
+	case l == nil:
+		return // below the threshold
+	case level >= LevelFatal:
+		l.Fatalf(format, a...)
+	case level == LevelError:
+		l.Errorf(format, a...)
+	default:
+		l.Logf(format, a...)
+	}
+}
+
 func HandleError(note string) {
-	switch ErrorMode {
-	case "":
-		_, err := fmt.Print(note)
-		if err != nil {
-			panic(err)
-		}
-	case "debug":
-		panic(note)
-	case "testing":
-		if T != nil {
-			T.Error(note + "\n" + string(debug.Stack()))
-		} else {
-			panic("Unset testing: " + note)
-		}
+	HandleLog(LevelError, "%s", withStack(note))
+}
+
+// withStack adds the stack trace to the note in testing mode
+func withStack(note string) string {
+	mu.Lock()
+	testing := ErrorMode == "testing"
+	mu.Unlock()
+	if testing {
+		note += "\n" + string(debug.Stack())
 	}
+	return note
 }
 
 func HandleFatal(note string) {
-	switch ErrorMode {
-	case "":
-		_, err := fmt.Print(note)
-		if err != nil {
-			panic(err)
-		}
-	case "debug":
-		panic(note)
-	case "testing":
-		if T != nil {
-			T.Fatal(note)
-		} else {
-			panic("Unset testing: " + note)
-		}
-	}
+	HandleLog(LevelFatal, "%s", note)
 }
 
 func HandleErrorf(format string, a ...interface{}) {
-	switch ErrorMode {
-	case "":
-		_, err := fmt.Printf(format, a...)
-		if err != nil {
-			panic(err)
-		}
-	case "debug":
-		panic(fmt.Sprintf(format, a...))
-	case "testing":
-		if T != nil {
-			s := fmt.Sprintf(format, a...)
-			_ = s
-			T.Errorf(format, a...)
-		} else {
-			panic("Unset testing: " + fmt.Sprintf(format, a...))
-		}
-	}
+	HandleLog(LevelError, format, a...)
 }
 
 func HandleFatalf(format string, a ...interface{}) {
-	switch ErrorMode {
-	case "":
-		_, err := fmt.Printf(format, a...)
-		if err != nil {
-			panic(err)
-		}
-	case "debug":
-		panic(fmt.Sprintf(format, a...))
-	case "testing":
-		if T != nil {
-			T.Fatalf(format, a...)
-		} else {
-			panic("Unset testing: " + fmt.Sprintf(format, a...))
-		}
-	}
+	HandleLog(LevelFatal, format, a...)
 }
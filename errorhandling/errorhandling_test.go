@@ -0,0 +1,205 @@
+package errorhandling
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandleLogThreshold(t *testing.T) {
+	oldMode, oldOutput, oldThreshold := ErrorMode, Output, GetThreshold()
+	defer func() { ErrorMode, Output = oldMode, oldOutput; SetThreshold(oldThreshold) }()
+
+	buf := new(bytes.Buffer)
+	ErrorMode = ""
+	Output = buf
+	SetThreshold(LevelWarn)
+
+	HandleLog(LevelDebug, "debug %d", 1)
+	HandleLog(LevelInfo, "info %d", 2)
+	if buf.Len() != 0 {
+		t.Errorf("Messages below the threshold should be suppressed, found %q", buf.String())
+	}
+
+	HandleLog(LevelWarn, "warn %d.", 3)
+	HandleErrorf("error %d.", 4)
+	if buf.String() != "warn 3.error 4." {
+		t.Errorf("Messages at or above the threshold should be emitted, found %q", buf.String())
+	}
+}
+
+func TestCaptureErrors(t *testing.T) {
+	oldMode, oldOutput := ErrorMode, Output
+	defer func() { ErrorMode = oldMode }()
+	ErrorMode = ""
+
+	s := CaptureErrors(func() {
+		HandleErrorf("%s failed: %d", "Execute", 7)
+	})
+	if s != "Execute failed: 7" {
+		t.Errorf("Expected the error to be captured, found %q", s)
+	}
+	if Output != oldOutput {
+		t.Error("CaptureErrors did not restore Output")
+	}
+}
+
+func TestUnknownErrorModeIsProduction(t *testing.T) {
+	oldMode := ErrorMode
+	defer func() { ErrorMode = oldMode }()
+	ErrorMode = "tseting"
+	warnedUnknownMode = false
+
+	s := CaptureErrors(func() { HandleErrorf("first") })
+	if !strings.Contains(s, "Unrecognized ErrorMode") || !strings.HasSuffix(s, "first") {
+		t.Errorf("Expected a warning and the error, found %q", s)
+	}
+
+	s = CaptureErrors(func() { HandleErrorf("second") })
+	if s != "second" {
+		t.Errorf("Expected only the error after the first warning, found %q", s)
+	}
+}
+
+func TestCollectMode(t *testing.T) {
+	oldMode := ErrorMode
+	defer func() { ErrorMode = oldMode }()
+	ErrorMode = "collect"
+	ResetCollectedErrors()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			HandleErrorf("node %d", i)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(CollectedErrors()) != 10 {
+		t.Errorf("Expected 10 collected errors, found %d", len(CollectedErrors()))
+	}
+
+	ResetCollectedErrors()
+	if len(CollectedErrors()) != 0 {
+		t.Error("ResetCollectedErrors did not clear the errors")
+	}
+}
+
+type captureLogger struct {
+	formats []string
+	args    [][]interface{}
+}
+
+func (c *captureLogger) Logf(format string, a ...interface{}) {}
+func (c *captureLogger) Errorf(format string, a ...interface{}) {
+	c.formats = append(c.formats, format)
+	c.args = append(c.args, a)
+}
+func (c *captureLogger) Fatalf(format string, a ...interface{}) {}
+
+func TestSetLogger(t *testing.T) {
+	c := new(captureLogger)
+	SetLogger(c)
+	defer SetLogger(nil)
+
+	HandleErrorf("vote from %s rejected: %d", "ID-01", 3)
+	if len(c.formats) != 1 || c.formats[0] != "vote from %s rejected: %d" {
+		t.Fatalf("Expected the format to be captured, found %v", c.formats)
+	}
+	if len(c.args[0]) != 2 || c.args[0][0] != "ID-01" || c.args[0][1] != 3 {
+		t.Errorf("Expected the args to be captured, found %v", c.args[0])
+	}
+}
+
+func TestConcurrentNodes(t *testing.T) {
+	oldMode := ErrorMode
+	defer func() { ErrorMode = oldMode }()
+
+	nodes := func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				WithNode(fmt.Sprintf("node%d", i)).HandleErrorf("bad vote %d;", i)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	ErrorMode = ""
+	s := CaptureErrors(nodes)
+	for i := 0; i < 10; i++ {
+		if !strings.Contains(s, fmt.Sprintf("[node%d] bad vote %d;", i, i)) {
+			t.Errorf("Error from node%d is missing or not attributed: %q", i, s)
+		}
+	}
+
+	ErrorMode = "collect"
+	ResetCollectedErrors()
+	nodes()
+	if len(CollectedErrors()) != 10 {
+		t.Errorf("Expected 10 collected errors, found %d", len(CollectedErrors()))
+	}
+	ResetCollectedErrors()
+}
+
+type fatalLogger struct {
+	captureLogger
+	fatals []string
+}
+
+func (c *fatalLogger) Fatalf(format string, a ...interface{}) {
+	c.fatals = append(c.fatals, fmt.Sprintf(format, a...))
+}
+
+func TestNodeLoggerMatchesPackage(t *testing.T) {
+	oldMode := ErrorMode
+	defer func() { ErrorMode = oldMode }()
+	c := new(fatalLogger)
+	SetLogger(c)
+	defer SetLogger(nil)
+
+	ErrorMode = "testing"
+	HandleError("package")
+	WithNode("node1").HandleError("node")
+	if len(c.args) != 2 {
+		t.Fatalf("Expected 2 errors, found %d", len(c.args))
+	}
+	for i, a := range c.args {
+		if s := fmt.Sprint(a...); !strings.Contains(s, "runtime/debug.Stack") {
+			t.Errorf("Error %d has no stack trace in testing mode: %q", i, s)
+		}
+	}
+
+	WithNode("node1").HandleFatal("gone")
+	if len(c.fatals) != 1 || c.fatals[0] != "[node1] gone" {
+		t.Errorf("Expected the fatal to be attributed to node1, found %v", c.fatals)
+	}
+}
+
+func TestSetThresholdWhileReporting(t *testing.T) {
+	oldMode, oldThreshold := ErrorMode, GetThreshold()
+	defer func() { ErrorMode = oldMode; SetThreshold(oldThreshold) }()
+	ErrorMode = "collect"
+	ResetCollectedErrors()
+	defer ResetCollectedErrors()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			WithNode(fmt.Sprintf("node%d", i)).HandleLog(LevelInfo, "info %d", i)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			SetThreshold(Level(i % 3))
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,152 @@
+package errorhandling
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Logger receives everything reported through the Handle* functions. Logf is used for the
+// levels below LevelError.
+type Logger interface {
+	Logf(format string, a ...interface{})
+	Errorf(format string, a ...interface{})
+	Fatalf(format string, a ...interface{})
+}
+
+// mu guards the package state so many simulated nodes can report at once. It covers logger,
+// warnedUnknownMode, T, threshold and every write to Output. ErrorMode is also set by -ldflags,
+// so only StartUnitTestErrorHandling writes it under mu
+var mu sync.Mutex
+
+var logger Logger // nil means use the logger for the ErrorMode
+
+// SetLogger installs l in place of the ErrorMode loggers. SetLogger(nil) goes back to the ErrorMode
+func SetLogger(l Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = l
+}
+
+var warnedUnknownMode bool // Only warn about an unrecognized ErrorMode once
+
+// currentLogger returns the Logger for a message at level, or nil if it is below the threshold
+func currentLogger(level Level) Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	if level < threshold && level < LevelFatal {
+		return nil
+	}
+	if logger != nil {
+		return logger
+	}
+
+	switch ErrorMode {
+	case "collect":
+		return collectLogger{}
+	case "debug":
+		return debugLogger{}
+	case "testing":
+		return testingLogger{}
+	default:
+		// "" is production. Anything else is a typo, which must not make errors vanish
+		if ErrorMode != "" && !warnedUnknownMode {
+			warnedUnknownMode = true
+			fmt.Fprintf(Output, "Unrecognized ErrorMode %q, handling errors as production\n", ErrorMode)
+		}
+		return productionLogger{}
+	}
+}
+
+// productionLogger prints everything to Output
+type productionLogger struct{}
+
+func (productionLogger) Logf(format string, a ...interface{})   { printf(format, a...) }
+func (productionLogger) Errorf(format string, a ...interface{}) { printf(format, a...) }
+func (productionLogger) Fatalf(format string, a ...interface{}) { printf(format, a...) }
+
+// printf writes to Output, one caller at a time
+func printf(format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	_, err := fmt.Fprintf(Output, format, a...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// debugLogger panics on errors so they are found during development
+type debugLogger struct{}
+
+func (debugLogger) Logf(format string, a ...interface{})   { printf(format, a...) }
+func (debugLogger) Errorf(format string, a ...interface{}) { panic(fmt.Sprintf(format, a...)) }
+func (debugLogger) Fatalf(format string, a ...interface{}) { panic(fmt.Sprintf(format, a...)) }
+
+// testingLogger reports to T
+type testingLogger struct{}
+
+func (testingLogger) Logf(format string, a ...interface{}) {
+	testingT(format, a...).Logf(format, a...)
+}
+func (testingLogger) Errorf(format string, a ...interface{}) {
+	testingT(format, a...).Errorf(format, a...)
+}
+func (testingLogger) Fatalf(format string, a ...interface{}) {
+	testingT(format, a...).Fatalf(format, a...)
+}
+
+func testingT(format string, a ...interface{}) *testing.T {
+	mu.Lock()
+	t := T
+	mu.Unlock()
+	if t == nil {
+		panic("Unset testing: " + fmt.Sprintf(format, a...))
+	}
+	return t
+}
+
+// collectLogger saves everything for CollectedErrors
+type collectLogger struct{}
+
+func (collectLogger) Logf(format string, a ...interface{})   { collect(format, a...) }
+func (collectLogger) Errorf(format string, a ...interface{}) { collect(format, a...) }
+func (collectLogger) Fatalf(format string, a ...interface{}) { collect(format, a...) }
+
+func collect(format string, a ...interface{}) {
+	collected.Lock()
+	collected.notes = append(collected.notes, fmt.Sprintf(format, a...))
+	collected.Unlock()
+}
+
+// NodeLogger reports like the package level Handle* functions, with every message
+// prefixed by the name of the node that reported it
+type NodeLogger struct {
+	id string
+}
+
+// WithNode returns a NodeLogger for a simulated node, safe to use from its own goroutine
+func WithNode(id string) *NodeLogger {
+	n := new(NodeLogger)
+	n.id = id
+	return n
+}
+
+func (n *NodeLogger) HandleLog(level Level, format string, a ...interface{}) {
+	HandleLog(level, "[%s] %s", n.id, fmt.Sprintf(format, a...))
+}
+
+func (n *NodeLogger) HandleError(note string) {
+	n.HandleLog(LevelError, "%s", withStack(note))
+}
+
+func (n *NodeLogger) HandleFatal(note string) {
+	n.HandleLog(LevelFatal, "%s", note)
+}
+
+func (n *NodeLogger) HandleErrorf(format string, a ...interface{}) {
+	n.HandleLog(LevelError, format, a...)
+}
+
+func (n *NodeLogger) HandleFatalf(format string, a ...interface{}) {
+	n.HandleLog(LevelFatal, format, a...)
+}
@@ -0,0 +1,83 @@
+package imessage
+
+// IMessageHandler can observe, rewrite, or drop a message in flight.
+// Returning drop as true removes the message.
+type IMessageHandler interface {
+	Process(msg IMessage) (out IMessage, drop bool)
+}
+
+// MessageChain runs messages through a list of handlers in order
+type MessageChain struct {
+	Handlers []IMessageHandler
+}
+
+// Chain returns a MessageChain of the handlers. A chain with no handlers passes every message through
+func Chain(handlers ...IMessageHandler) *MessageChain {
+	c := new(MessageChain)
+	c.Handlers = handlers
+	return c
+}
+
+// Process makes a chain a handler itself, so chains can be nested
+func (c *MessageChain) Process(msg IMessage) (IMessage, bool) {
+	for _, h := range c.Handlers {
+		var drop bool
+		msg, drop = h.Process(msg)
+		if drop {
+			return nil, true
+		}
+	}
+	return msg, false
+}
+
+// Apply runs each message through the chain and returns the ones that were not dropped
+func (c *MessageChain) Apply(messages []IMessage) []IMessage {
+	var out []IMessage
+	for _, m := range messages {
+		if p, drop := c.Process(m); !drop {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// MakeMessageArray is MakeMessageArray with the messages run through the chain
+func (c *MessageChain) MakeMessageArray(messages ...IMessage) []IMessage {
+	return c.Apply(MakeMessageArray(messages...))
+}
+
+// MakeMessageArrayFromArray is MakeMessageArrayFromArray with the new messages run through
+// the chain. The array is assumed to have been through the chain already.
+func (c *MessageChain) MakeMessageArrayFromArray(array []IMessage, messages ...IMessage) []IMessage {
+	return MakeMessageArrayFromArray(array, c.Apply(messages)...)
+}
+
+// DelayHandler is a reference handler that injects latency. Each message is held back until
+// Delay more messages have passed through, and the oldest held message is sent in its place.
+type DelayHandler struct {
+	Delay int
+	held  []IMessage
+}
+
+func NewDelayHandler(delay int) *DelayHandler {
+	d := new(DelayHandler)
+	d.Delay = delay
+	return d
+}
+
+func (d *DelayHandler) Process(msg IMessage) (IMessage, bool) {
+	d.held = append(d.held, msg)
+	if len(d.held) <= d.Delay {
+		return nil, true
+	}
+	out := d.held[0]
+	d.held = d.held[1:]
+	return out, false
+}
+
+// Flush returns the messages still held back
+func (d *DelayHandler) Flush() []IMessage {
+	held := d.held
+	d.held = nil
+	return held
+}
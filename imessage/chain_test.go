@@ -0,0 +1,75 @@
+package imessage_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/electiontesting/election"
+	. "github.com/FactomProject/electiontesting/imessage"
+	"github.com/FactomProject/electiontesting/messages"
+	"github.com/FactomProject/electiontesting/primitives"
+	"github.com/FactomProject/electiontesting/testhelper"
+)
+
+// dropVotes drops every level 0 vote
+type dropVotes struct{}
+
+func (dropVotes) Process(msg IMessage) (IMessage, bool) {
+	return msg, msg.Type() == MessageType_Vote
+}
+
+// runElection broadcasts every message to all the feds, sending their responses through the chain
+func runElection(chain *MessageChain) []*election.Election {
+	ah := testhelper.NewAuthSetHelper(3, 1)
+	var loc primitives.ProcessListLocation
+	vol := messages.NewVolunteerMessage(messages.NewEomMessage(ah.GetAuds()[0], loc), ah.GetAuds()[0])
+
+	var elections []*election.Election
+	for _, f := range ah.GetFeds() {
+		elections = append(elections, election.NewElection(f, ah.GetAuthSet()))
+	}
+
+	queue := chain.MakeMessageArray(&vol)
+	for steps := 0; len(queue) > 0 && steps < 1000; steps++ {
+		msg := queue[0]
+		queue = queue[1:]
+		for _, e := range elections {
+			resp, _ := e.Execute(msg, 0)
+			if resp != nil {
+				queue = chain.MakeMessageArrayFromArray(queue, resp)
+			}
+		}
+	}
+	return elections
+}
+
+func TestChainDropVotesStallsElection(t *testing.T) {
+	for i, e := range runElection(Chain()) {
+		if e.CurrentVote.Rank < 0 {
+			t.Errorf("Fed %d should have voted with all messages delivered", i)
+		}
+	}
+
+	for i, e := range runElection(Chain(dropVotes{})) {
+		if e.CurrentVote.Rank >= 0 {
+			t.Errorf("Fed %d voted at rank %d without any level 0 votes", i, e.CurrentVote.Rank)
+		}
+	}
+}
+
+func TestDelayHandler(t *testing.T) {
+	d := NewDelayHandler(2)
+	var in []IMessage
+	for i := 0; i < 5; i++ {
+		v := messages.NewVoteMessage(messages.VolunteerMessage{}, primitives.NewIdentityFromInt(i))
+		in = append(in, &v)
+	}
+
+	out := Chain(d).Apply(in)
+	if len(out) != 3 || out[0] != in[0] || out[2] != in[2] {
+		t.Errorf("Expected the first 3 messages in order, found %d messages", len(out))
+	}
+	held := d.Flush()
+	if len(held) != 2 || held[0] != in[3] || held[1] != in[4] {
+		t.Errorf("Expected the last 2 messages to be held, found %d", len(held))
+	}
+}
@@ -1,11 +1,68 @@
 package imessage
 
+import (
+	"fmt"
+
+	. "github.com/FactomProject/electiontesting/errorhandling"
+)
+
 type IMessage interface {
 	String() string
 	ReadString(s string)
+	Type() MessageType
 }
 
 type Taggable interface {
 	Tag() [32]byte
 	TagMessage(tag [32]byte)
 }
+
+// MessageType lets a message be dispatched without a type switch
+type MessageType int
+
+const (
+	MessageType_Unknown MessageType = iota
+	MessageType_Volunteer
+	MessageType_Vote
+	MessageType_LeaderLevel
+	MessageType_MajorityDecision
+	MessageType_Insist
+	MessageType_IAck
+	MessageType_Publish
+)
+
+func (t MessageType) String() string {
+	switch t {
+	case MessageType_Unknown:
+		return "Unknown"
+	case MessageType_Volunteer:
+		return "Volunteer"
+	case MessageType_Vote:
+		return "Vote"
+	case MessageType_LeaderLevel:
+		return "LeaderLevel"
+	case MessageType_MajorityDecision:
+		return "MajorityDecision"
+	case MessageType_Insist:
+		return "Insist"
+	case MessageType_IAck:
+		return "IAck"
+	case MessageType_Publish:
+		return "Publish"
+	default:
+		return fmt.Sprintf("INVALID:%d", int(t))
+	}
+}
+
+// Handlers holds the function to call for each MessageType
+type Handlers map[MessageType]func(IMessage)
+
+// Dispatch calls the handler for the message's type. A message with no handler is logged and dropped.
+func (h Handlers) Dispatch(msg IMessage) {
+	f, ok := h[msg.Type()]
+	if !ok {
+		HandleLog(LevelWarn, "No handler for %s message, dropped: %s", msg.Type().String(), msg.String())
+		return
+	}
+	f(msg)
+}
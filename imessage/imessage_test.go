@@ -0,0 +1,37 @@
+package imessage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/electiontesting/errorhandling"
+	. "github.com/FactomProject/electiontesting/imessage"
+	"github.com/FactomProject/electiontesting/messages"
+)
+
+func TestHandlersDispatch(t *testing.T) {
+	votes := 0
+	h := Handlers{
+		MessageType_Vote: func(IMessage) { votes++ },
+	}
+
+	h.Dispatch(new(messages.VoteMessage))
+	if votes != 1 {
+		t.Errorf("Vote handler was called %d times, expected 1", votes)
+	}
+
+	// No handler for volunteers, so it goes to the logged default
+	s := errorhandling.CaptureErrors(func() { h.Dispatch(new(messages.VolunteerMessage)) })
+	if !strings.Contains(s, "No handler for Volunteer message") {
+		t.Errorf("Expected the unhandled message to be logged, found %q", s)
+	}
+
+	// Neither does a message of an unknown type
+	s = errorhandling.CaptureErrors(func() { h.Dispatch(messages.EomMessage{}) })
+	if !strings.Contains(s, "No handler for Unknown message") {
+		t.Errorf("Expected the unknown message to be logged, found %q", s)
+	}
+	if votes != 1 {
+		t.Error("Unhandled message went to the vote handler")
+	}
+}
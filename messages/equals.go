@@ -0,0 +1,84 @@
+package messages
+
+import (
+	. "github.com/FactomProject/electiontesting/primitives"
+)
+
+// Equals compares messages by value so two nodes can be checked for agreement.
+// Maps are compared as sets, and message tags are local bookkeeping so they are ignored.
+
+func (r *VolunteerMessage) Equals(o *VolunteerMessage) bool {
+	return r.Id == o.Id && r.Eom == o.Eom && r.FaultMsg == o.FaultMsg && r.Signer == o.Signer
+}
+
+func (r *VoteMessage) Equals(o *VoteMessage) bool {
+	return r.Signer == o.Signer && r.Volunteer.Equals(&o.Volunteer)
+}
+
+func (r *MajorityDecisionMessage) Equals(o *MajorityDecisionMessage) bool {
+	if r.Signer != o.Signer || !r.Volunteer.Equals(&o.Volunteer) || len(r.MajorityVotes) != len(o.MajorityVotes) {
+		return false
+	}
+	for k, v := range r.MajorityVotes {
+		if ov, ok := o.MajorityVotes[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return majorityDecisionsEqual(r.OtherMajorityDecisions, o.OtherMajorityDecisions)
+}
+
+func (r *InsistMessage) Equals(o *InsistMessage) bool {
+	if r.Signer != o.Signer || !majorityDecisionsEqual(r.MajorityMajorityDecisions, o.MajorityMajorityDecisions) {
+		return false
+	}
+	if len(r.OtherInsists) != len(o.OtherInsists) {
+		return false
+	}
+	for k, v := range r.OtherInsists {
+		ov, ok := o.OtherInsists[k]
+		if !ok || !v.Equals(&ov) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *IAckMessage) Equals(o *IAckMessage) bool {
+	return r.Insist.Equals(&o.Insist) && identitySetsEqual(r.Signers, o.Signers)
+}
+
+func (r *PublishMessage) Equals(o *PublishMessage) bool {
+	return r.Signer == o.Signer && r.Insist.Equals(&o.Insist) && identitySetsEqual(r.MajorityIAckMessages, o.MajorityIAckMessages)
+}
+
+func majorityDecisionsEqual(a, b map[Identity]MajorityDecisionMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		ov, ok := b[k]
+		if !ok || !v.Equals(&ov) {
+			return false
+		}
+	}
+	return true
+}
+
+// identitySetsEqual compares the identities marked true in each map
+func identitySetsEqual(a, b map[Identity]bool) bool {
+	count := 0
+	for k, v := range a {
+		if v {
+			if !b[k] {
+				return false
+			}
+			count++
+		}
+	}
+	for _, v := range b {
+		if v {
+			count--
+		}
+	}
+	return count == 0
+}
@@ -0,0 +1,52 @@
+package messages
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/electiontesting/primitives"
+)
+
+func TestMajorityDecisionMessageEquals(t *testing.T) {
+	var loc ProcessListLocation
+	vol := NewVolunteerMessage(NewEomMessage(NewIdentityFromInt(10), loc), NewIdentityFromInt(10))
+
+	a := NewMajorityDecisionMessage(vol, make(map[Identity]SignedMessage), NewIdentityFromInt(1))
+	b := NewMajorityDecisionMessage(vol, make(map[Identity]SignedMessage), NewIdentityFromInt(1))
+	for i := 1; i <= 3; i++ {
+		a.MajorityVotes[NewIdentityFromInt(i)] = SignedMessage{NewIdentityFromInt(i)}
+	}
+	for i := 3; i >= 1; i-- {
+		b.MajorityVotes[NewIdentityFromInt(i)] = SignedMessage{NewIdentityFromInt(i)}
+	}
+
+	if !a.Equals(&b) || !b.Equals(&a) {
+		t.Error("Majority decisions with the same votes should be equal")
+	}
+
+	// Tags do not matter
+	b.Volunteer.TagMessage([32]byte{1})
+	if !a.Equals(&b) {
+		t.Error("Tagging the volunteer should not change equality")
+	}
+
+	b.MajorityVotes[NewIdentityFromInt(4)] = SignedMessage{NewIdentityFromInt(4)}
+	if a.Equals(&b) {
+		t.Error("Majority decisions with different votes should not be equal")
+	}
+
+	// Embedded in an insist, iack, and publish
+	ia := NewInsistenceMessage(map[Identity]MajorityDecisionMessage{a.Signer: a}, NewIdentityFromInt(2))
+	ib := NewInsistenceMessage(map[Identity]MajorityDecisionMessage{b.Signer: b}, NewIdentityFromInt(2))
+	if ia.Equals(&ib) {
+		t.Error("Insists with different majority decisions should not be equal")
+	}
+	acka, ackb := NewIAckMessage(ia, NewIdentityFromInt(3)), NewIAckMessage(ia, NewIdentityFromInt(3))
+	if !acka.Equals(&ackb) {
+		t.Error("IAcks with the same insist and signers should be equal")
+	}
+	pa := NewPublishMessage(ia, ia.Signer, acka.Signers)
+	pb := NewPublishMessage(ia, ia.Signer, map[Identity]bool{})
+	if pa.Equals(&pb) {
+		t.Error("Publishes with different iacks should not be equal")
+	}
+}
@@ -15,8 +15,9 @@ var Nothing NoMessage
 
 type NoMessage struct{}
 
-func (r *NoMessage) String() string      { return jsonMarshal(r) }
-func (r *NoMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *NoMessage) String() string             { return jsonMarshal(r) }
+func (r *NoMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *NoMessage) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 
 var embeddedMesssageRegEx *regexp.Regexp
 
@@ -69,8 +70,9 @@ type TaggedMessage struct {
 func (r *TaggedMessage) Tag() [32]byte {
 	return r.tag
 }
-func (r *TaggedMessage) String() string      { return jsonMarshal(r) }
-func (r *TaggedMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *TaggedMessage) String() string             { return jsonMarshal(r) }
+func (r *TaggedMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *TaggedMessage) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 func (r *TaggedMessage) TagMessage(tag [32]byte) {
 	r.tag = tag
 }
@@ -79,16 +81,18 @@ type SignedMessage struct {
 	Signer Identity
 }
 
-func (r *SignedMessage) String() string      { return jsonMarshal(r) }
-func (r *SignedMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *SignedMessage) String() string             { return jsonMarshal(r) }
+func (r *SignedMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *SignedMessage) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 
 type EomMessage struct {
 	ProcessListLocation
 	SignedMessage
 }
 
-func (r EomMessage) String() string      { return jsonMarshal(r) }
-func (r EomMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r EomMessage) String() string             { return jsonMarshal(r) }
+func (r EomMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r EomMessage) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 
 func NewEomMessage(identity Identity, loc ProcessListLocation) EomMessage {
 	var e EomMessage
@@ -106,8 +110,9 @@ type FaultMsg struct {
 	SignedMessage
 }
 
-func (r *FaultMsg) String() string      { return jsonMarshal(r) }
-func (r *FaultMsg) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *FaultMsg) String() string             { return jsonMarshal(r) }
+func (r *FaultMsg) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *FaultMsg) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 
 func NewFaultMessage(victim Identity, pl ProcessListLocation, r int, signer Identity) FaultMsg {
 	var fault FaultMsg = FaultMsg{victim, pl, r, SignedMessage{signer}}
@@ -122,8 +127,9 @@ type DbsigMessage struct {
 	SignedMessage
 }
 
-func (r *DbsigMessage) String() string      { return jsonMarshal(r) }
-func (r *DbsigMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *DbsigMessage) String() string             { return jsonMarshal(r) }
+func (r *DbsigMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *DbsigMessage) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 
 func NewDBSigMessage(identity Identity, eom EomMessage, prev Hash) DbsigMessage {
 	var dbs DbsigMessage
@@ -140,8 +146,9 @@ type AuthChangeMessage struct {
 	SignedMessage
 }
 
-func (r *AuthChangeMessage) String() string      { return jsonMarshal(r) }
-func (r *AuthChangeMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *AuthChangeMessage) String() string             { return jsonMarshal(r) }
+func (r *AuthChangeMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *AuthChangeMessage) Type() imessage.MessageType { return imessage.MessageType_Unknown }
 
 // ------------------------------------------------------------------------------------------------------------------
 type VolunteerMessage struct {
@@ -154,8 +161,9 @@ type VolunteerMessage struct {
 
 var _ imessage.IMessage = (*VolunteerMessage)(nil)
 
-func (r *VolunteerMessage) String() string      { return jsonMarshal(r) }
-func (r *VolunteerMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *VolunteerMessage) String() string             { return jsonMarshal(r) }
+func (r *VolunteerMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *VolunteerMessage) Type() imessage.MessageType { return imessage.MessageType_Volunteer }
 
 func NewVolunteerMessageWithoutEOM(identity Identity) VolunteerMessage {
 	var v VolunteerMessage
@@ -235,8 +243,9 @@ func (a *LeaderLevelMessage) Copy() *LeaderLevelMessage {
 	return &b
 }
 
-func (r *LeaderLevelMessage) String() string      { return jsonMarshal(r) }
-func (r *LeaderLevelMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *LeaderLevelMessage) String() string             { return jsonMarshal(r) }
+func (r *LeaderLevelMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *LeaderLevelMessage) Type() imessage.MessageType { return imessage.MessageType_LeaderLevel }
 func NewLeaderLevelMessage(self Identity, rank, level int, v VolunteerMessage) LeaderLevelMessage {
 	var l LeaderLevelMessage
 	l.Signer = self
@@ -268,8 +277,9 @@ func (a *VoteMessage) Copy() *VoteMessage {
 
 	return b
 }
-func (r *VoteMessage) String() string      { return jsonMarshal(r) }
-func (r *VoteMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *VoteMessage) String() string             { return jsonMarshal(r) }
+func (r *VoteMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *VoteMessage) Type() imessage.MessageType { return imessage.MessageType_Vote }
 
 func NewVoteMessage(vol VolunteerMessage, self Identity) VoteMessage {
 	var vote VoteMessage
@@ -294,6 +304,9 @@ type MajorityDecisionMessage struct {
 
 func (r *MajorityDecisionMessage) String() string      { return jsonMarshal(r) }
 func (r *MajorityDecisionMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *MajorityDecisionMessage) Type() imessage.MessageType {
+	return imessage.MessageType_MajorityDecision
+}
 
 func NewMajorityDecisionMessage(volunteer VolunteerMessage, votes map[Identity]SignedMessage, self Identity) MajorityDecisionMessage {
 	var mj MajorityDecisionMessage
@@ -314,8 +327,9 @@ type InsistMessage struct {
 	OtherInsists map[Identity]InsistMessage
 }
 
-func (r *InsistMessage) String() string      { return jsonMarshal(r) }
-func (r *InsistMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *InsistMessage) String() string             { return jsonMarshal(r) }
+func (r *InsistMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *InsistMessage) Type() imessage.MessageType { return imessage.MessageType_Insist }
 
 func NewInsistenceMessage(mds map[Identity]MajorityDecisionMessage, identity Identity) InsistMessage {
 	var i InsistMessage
@@ -333,8 +347,53 @@ type IAckMessage struct {
 	Signers map[Identity]bool
 }
 
-func (r *IAckMessage) String() string      { return jsonMarshal(r) }
-func (r *IAckMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *IAckMessage) String() string             { return jsonMarshal(r) }
+func (r *IAckMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *IAckMessage) Type() imessage.MessageType { return imessage.MessageType_IAck }
+
+// iackJSON is how an IAckMessage is serialized. The signers are a sorted list rather
+// than a map so the same set of signers always serializes to the same bytes
+type iackJSON struct {
+	Insist  InsistMessage
+	Signers []Identity
+}
+
+func (r *IAckMessage) MarshalJSON() ([]byte, error) {
+	var j iackJSON
+	j.Insist = r.Insist
+	j.Signers = signedIdentities(r.Signers)
+	return json.Marshal(&j)
+}
+
+func (r *IAckMessage) UnmarshalJSON(data []byte) error {
+	var j iackJSON
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	r.Insist = j.Insist
+	r.Signers = identitySet(j.Signers)
+	return nil
+}
+
+// signedIdentities lists the identities marked true in ascending order
+func signedIdentities(m map[Identity]bool) []Identity {
+	var ids []Identity
+	for _, id := range SortedIdentities(m) {
+		if m[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func identitySet(ids []Identity) map[Identity]bool {
+	m := make(map[Identity]bool)
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m
+}
 
 func NewIAckMessage(insist InsistMessage, identity Identity) IAckMessage {
 	var iack IAckMessage
@@ -352,8 +411,36 @@ type PublishMessage struct {
 	SignedMessage
 }
 
-func (r *PublishMessage) String() string      { return jsonMarshal(r) }
-func (r *PublishMessage) ReadString(s string) { jsonUnmarshal(r, s) }
+func (r *PublishMessage) String() string             { return jsonMarshal(r) }
+func (r *PublishMessage) ReadString(s string)        { jsonUnmarshal(r, s) }
+func (r *PublishMessage) Type() imessage.MessageType { return imessage.MessageType_Publish }
+
+// publishJSON is how a PublishMessage is serialized, with the iacks listed like iackJSON
+type publishJSON struct {
+	Insist               InsistMessage
+	MajorityIAckMessages []Identity
+	SignedMessage
+}
+
+func (r *PublishMessage) MarshalJSON() ([]byte, error) {
+	var j publishJSON
+	j.Insist = r.Insist
+	j.MajorityIAckMessages = signedIdentities(r.MajorityIAckMessages)
+	j.SignedMessage = r.SignedMessage
+	return json.Marshal(&j)
+}
+
+func (r *PublishMessage) UnmarshalJSON(data []byte) error {
+	var j publishJSON
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	r.Insist = j.Insist
+	r.MajorityIAckMessages = identitySet(j.MajorityIAckMessages)
+	r.SignedMessage = j.SignedMessage
+	return nil
+}
 
 func NewPublishMessage(insist InsistMessage, identity Identity, iackMap map[Identity]bool) PublishMessage {
 	var p PublishMessage
@@ -2,7 +2,9 @@ package messages
 
 import (
 	. "github.com/FactomProject/electiontesting/errorhandling"
+	"github.com/FactomProject/electiontesting/imessage"
 	. "github.com/FactomProject/electiontesting/primitives"
+	"strings"
 	"testing"
 )
 
@@ -98,3 +100,182 @@ func TestVoteMessageReadString(t *testing.T) {
 		t.Errorf("VoteMessages.ReadString(\"%s\")", s)
 	}
 }
+
+func TestMessageHashStableAcrossSerialization(t *testing.T) {
+	T = t // Set test for error handling
+	var loc ProcessListLocation
+	loc.ReadString("3/2/1")
+	eom := NewEomMessage(NewIdentityFromInt(1), loc)
+	vol := NewVolunteerMessage(eom, NewIdentityFromInt(2))
+	vote := NewVoteMessage(vol, NewIdentityFromInt(3))
+
+	h := MessageHash(&vote)
+
+	var restored VoteMessage
+	restored.ReadString(vote.String())
+	if MessageHash(&restored) != h {
+		t.Errorf("VoteMessage hash changed after a JSON round trip")
+	}
+
+	// Tags are local bookkeeping and must not change the id
+	vote.TagMessage([32]byte{1})
+	if MessageHash(&vote) != h {
+		t.Errorf("VoteMessage hash changed after tagging")
+	}
+}
+
+// proofFixture builds a majority decision, insist, iack and publish backed by feds 1 to 3
+func proofFixture() (MajorityDecisionMessage, InsistMessage, IAckMessage, PublishMessage) {
+	var loc ProcessListLocation
+	loc.ReadString("3/2/1")
+	vol := NewVolunteerMessage(NewEomMessage(NewIdentityFromInt(10), loc), NewIdentityFromInt(10))
+
+	votes := make(map[Identity]SignedMessage)
+	mds := make(map[Identity]MajorityDecisionMessage)
+	for i := 1; i <= 3; i++ {
+		votes[NewIdentityFromInt(i)] = SignedMessage{NewIdentityFromInt(i)}
+	}
+	for i := 1; i <= 3; i++ {
+		mds[NewIdentityFromInt(i)] = NewMajorityDecisionMessage(vol, votes, NewIdentityFromInt(i))
+	}
+	insist := NewInsistenceMessage(mds, NewIdentityFromInt(1))
+	iack := NewIAckMessage(insist, NewIdentityFromInt(2))
+	iack.Signers[NewIdentityFromInt(3)] = true
+	publish := NewPublishMessage(insist, NewIdentityFromInt(1), iack.Signers)
+	return mds[NewIdentityFromInt(1)], insist, iack, publish
+}
+
+func TestMessageHashProofMessages(t *testing.T) {
+	T = t // Set test for error handling
+	md, insist, iack, publish := proofFixture()
+
+	var rmd MajorityDecisionMessage
+	var rinsist InsistMessage
+	var riack IAckMessage
+	var rpublish PublishMessage
+	for _, c := range []struct {
+		name     string
+		msg, got imessage.IMessage
+	}{
+		{"MajorityDecisionMessage", &md, &rmd},
+		{"InsistMessage", &insist, &rinsist},
+		{"IAckMessage", &iack, &riack},
+		{"PublishMessage", &publish, &rpublish},
+	} {
+		s := c.msg.String()
+		if !strings.Contains(s, "ID-") {
+			t.Fatalf("%s did not serialize: %q", c.name, s)
+		}
+		c.got.ReadString(s)
+		if c.got.String() != s {
+			t.Errorf("%s.ReadString(\"%s\") did not restore the message", c.name, s)
+		}
+		if MessageHash(c.got) != MessageHash(c.msg) {
+			t.Errorf("%s hash changed after a JSON round trip", c.name)
+		}
+	}
+
+	// Distinct messages must not share a hash
+	other := NewMajorityDecisionMessage(md.Volunteer, map[Identity]SignedMessage{NewIdentityFromInt(4): {NewIdentityFromInt(4)}}, NewIdentityFromInt(4))
+	if MessageHash(&other) == MessageHash(&md) {
+		t.Error("Majority decisions with different signers and votes have the same hash")
+	}
+	otherInsist := NewInsistenceMessage(map[Identity]MajorityDecisionMessage{other.Signer: other}, NewIdentityFromInt(4))
+	otherIAck := NewIAckMessage(otherInsist, NewIdentityFromInt(2))
+	otherIAck.Signers[NewIdentityFromInt(3)] = true
+	if MessageHash(&otherIAck) == MessageHash(&iack) {
+		t.Error("IAcks for different insists have the same hash")
+	}
+}
+
+func TestProofSerializationIsByteIdentical(t *testing.T) {
+	T = t // Set test for error handling
+	md, insist, _, publish := proofFixture()
+
+	// Build the same proofs again, adding to every map in the opposite order
+	votes := make(map[Identity]SignedMessage)
+	mds := make(map[Identity]MajorityDecisionMessage)
+	iacks := make(map[Identity]bool)
+	for i := 3; i >= 1; i-- {
+		votes[NewIdentityFromInt(i)] = SignedMessage{NewIdentityFromInt(i)}
+	}
+	for i := 3; i >= 1; i-- {
+		mds[NewIdentityFromInt(i)] = NewMajorityDecisionMessage(md.Volunteer, votes, NewIdentityFromInt(i))
+	}
+	iacks[NewIdentityFromInt(3)] = true
+	iacks[NewIdentityFromInt(2)] = true
+	insist2 := NewInsistenceMessage(mds, insist.Signer)
+	md2 := mds[md.Signer]
+	publish2 := NewPublishMessage(insist2, publish.Signer, iacks)
+
+	if md.String() != md2.String() || insist.String() != insist2.String() || publish.String() != publish2.String() {
+		t.Errorf("The same proofs serialized differently:\n%s\n%s", publish.String(), publish2.String())
+	}
+
+	// A publish that lists a false iack is the same publish
+	iacks[NewIdentityFromInt(4)] = false
+	publish3 := NewPublishMessage(insist2, publish.Signer, iacks)
+	if !publish.Equals(&publish3) || MessageHash(&publish) != MessageHash(&publish3) {
+		t.Errorf("Equal publishes hash differently:\n%s\n%s", publish.String(), publish3.String())
+	}
+
+	// Keys come out in SortedIdentities order
+	s := insist.String()
+	last := -1
+	for _, id := range SortedIdentities(insist.MajorityMajorityDecisions) {
+		n := strings.Index(s, `"`+id.String()+`":{"Volunteer"`)
+		if n <= last {
+			t.Fatalf("Majority decision from %s is not in sorted order in %s", id.String(), s)
+		}
+		last = n
+	}
+}
+
+func TestIAckMessageSignersSorted(t *testing.T) {
+	T = t // Set test for error handling
+	_, insist, _, _ := proofFixture()
+
+	a := NewIAckMessage(insist, NewIdentityFromInt(1))
+	a.Signers[NewIdentityFromInt(2)] = true
+	a.Signers[NewIdentityFromInt(3)] = true
+
+	b := NewIAckMessage(insist, NewIdentityFromInt(3))
+	b.Signers[NewIdentityFromInt(1)] = true
+	b.Signers[NewIdentityFromInt(2)] = true
+	b.Signers[NewIdentityFromInt(4)] = false // Not a signer
+
+	if a.String() != b.String() {
+		t.Errorf("Same signers serialized differently:\n%s\n%s", a.String(), b.String())
+	}
+
+	var r IAckMessage
+	r.ReadString(b.String())
+	if len(r.Signers) != 3 || !r.Signers[NewIdentityFromInt(2)] || r.Insist.String() != insist.String() {
+		t.Errorf("IAckMessage.ReadString(\"%s\") did not restore the message", b.String())
+	}
+	if _, ok := r.Signers[NewIdentityFromInt(4)]; ok {
+		t.Error("An identity marked false came back as a signer")
+	}
+}
+
+func TestMessageType(t *testing.T) {
+	T = t // Set test for error handling
+	for _, c := range []struct {
+		msg imessage.IMessage
+		exp imessage.MessageType
+	}{
+		{new(VolunteerMessage), imessage.MessageType_Volunteer},
+		{new(VoteMessage), imessage.MessageType_Vote},
+		{new(LeaderLevelMessage), imessage.MessageType_LeaderLevel},
+		{new(MajorityDecisionMessage), imessage.MessageType_MajorityDecision},
+		{new(InsistMessage), imessage.MessageType_Insist},
+		{new(IAckMessage), imessage.MessageType_IAck},
+		{new(PublishMessage), imessage.MessageType_Publish},
+		{new(EomMessage), imessage.MessageType_Unknown},
+		{new(FaultMsg), imessage.MessageType_Unknown},
+	} {
+		if c.msg.Type() != c.exp {
+			t.Errorf("%T.Type() = %s, expected %s", c.msg, c.msg.Type().String(), c.exp.String())
+		}
+	}
+}
@@ -1,6 +1,17 @@
 package messages
 
-import "github.com/FactomProject/electiontesting/primitives"
+import (
+	"crypto/sha256"
+
+	"github.com/FactomProject/electiontesting/imessage"
+	"github.com/FactomProject/electiontesting/primitives"
+)
+
+// MessageHash returns an id for the message that is stable across serialization. It is
+// computed from the String() form only, so unexported state like tags is never included.
+func MessageHash(msg imessage.IMessage) primitives.Hash {
+	return sha256.Sum256([]byte(msg.String()))
+}
 
 func GetSigner(msg interface{}) primitives.Identity {
 	switch msg.(type) {
@@ -0,0 +1,39 @@
+package messages
+
+import (
+	. "github.com/FactomProject/electiontesting/primitives"
+)
+
+// Valid is true if the votes in the majority decision are a quorum of the current feds.
+// Votes are only counted under the identity that signed them.
+func (r *MajorityDecisionMessage) Valid(a AuthSet) bool {
+	signers := make(map[Identity]bool)
+	for k, v := range r.MajorityVotes {
+		signers[k] = k == v.Signer
+	}
+	return a.HasQuorum(signers)
+}
+
+// Valid is true if the insist carries a quorum of valid majority decisions, all for the same volunteer
+func (r *InsistMessage) Valid(a AuthSet) bool {
+	signers := make(map[Identity]bool)
+	var vol *VolunteerMessage
+	for k, md := range r.MajorityMajorityDecisions {
+		if !md.Valid(a) {
+			return false
+		}
+		if vol == nil {
+			vol = &md.Volunteer
+		} else if !vol.Equals(&md.Volunteer) {
+			return false
+		}
+		signers[k] = k == md.Signer
+	}
+	return a.HasQuorum(signers)
+}
+
+// Valid is true if the publish comes from the insisting server, the insist is valid,
+// and the iacks are a quorum of the current feds
+func (r *PublishMessage) Valid(a AuthSet) bool {
+	return r.Signer == r.Insist.Signer && r.Insist.Valid(a) && a.HasQuorum(r.MajorityIAckMessages)
+}
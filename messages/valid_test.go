@@ -0,0 +1,56 @@
+package messages
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/electiontesting/primitives"
+)
+
+func TestPublishMessageValid(t *testing.T) {
+	a := NewAuthSet()
+	var feds []Identity
+	for i := 0; i < 5; i++ {
+		feds = append(feds, NewIdentityFromInt(i))
+		a.Add(feds[i], 1)
+	}
+	aud := NewIdentityFromInt(100)
+	a.Add(aud, 0)
+
+	var loc ProcessListLocation
+	vol := NewVolunteerMessage(NewEomMessage(aud, loc), aud)
+
+	votes := make(map[Identity]SignedMessage)
+	for _, f := range feds[:3] {
+		votes[f] = SignedMessage{f}
+	}
+	mds := make(map[Identity]MajorityDecisionMessage)
+	for _, f := range feds[:3] {
+		mds[f] = NewMajorityDecisionMessage(vol, votes, f)
+	}
+	insist := NewInsistenceMessage(mds, feds[0])
+
+	iacks := map[Identity]bool{feds[0]: true, feds[1]: true, feds[2]: true}
+	p := NewPublishMessage(insist, feds[0], iacks)
+	if !p.Valid(*a) {
+		t.Error("Publish with a majority of iacks should be valid")
+	}
+
+	// Too few iacks, an audit iack does not count
+	few := NewPublishMessage(insist, feds[0], map[Identity]bool{feds[0]: true, feds[1]: true, aud: true})
+	if few.Valid(*a) {
+		t.Error("Publish with 2 of 5 fed iacks should be rejected")
+	}
+
+	// Someone other than the insisting server
+	other := NewPublishMessage(insist, feds[1], iacks)
+	if other.Valid(*a) {
+		t.Error("Publish from a server that did not insist should be rejected")
+	}
+
+	// The insist itself has too few majority decisions
+	delete(mds, feds[2])
+	weak := NewPublishMessage(NewInsistenceMessage(mds, feds[0]), feds[0], iacks)
+	if weak.Valid(*a) {
+		t.Error("Publish of an insist with 2 of 5 majority decisions should be rejected")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 
 	"bytes"
 
@@ -155,7 +156,7 @@ func (a *AuthSet) VMForIdentity(id Identity, location MinuteLocation) int {
 func (a *AuthSet) Sort() {
 	for i := 1; i < len(a.IdentityList); i++ {
 		for j := 0; j < len(a.IdentityList)-i; j++ {
-			if a.IdentityList[j+1].less(a.IdentityList[j]) {
+			if a.IdentityList[j+1].Less(a.IdentityList[j]) {
 				// Swap in both lists, change the index in the map
 				a.IdentityList[j], a.IdentityList[j+1] = a.IdentityList[j+1], a.IdentityList[j]
 				a.StatusArray[j], a.StatusArray[j+1] = a.StatusArray[j+1], a.StatusArray[j]
@@ -178,15 +179,26 @@ func (a *AuthSet) AddHash(id interfaces.IHash, status int) int {
 	return a.Add(Identity(id.Fixed()), status)
 }
 
+// Add puts the identity in the authority set and returns its index. An identity can only be
+// added once, a duplicate would be counted twice towards the majority.
 func (a *AuthSet) Add(id Identity, status int) int {
+	if index, ok := a.IdentityMap[id]; ok {
+		HandleErrorf("AuthSet.Add(%v) failed: duplicate identity", id.String())
+		return index
+	}
+
 	index := len(a.IdentityList)
 	a.IdentityMap[id] = index
 	a.IdentityList = append(a.IdentityList, id)
 	a.StatusArray = append(a.StatusArray, status)
 	// a.Sort()
 
+	// Volunteer priority follows CompareIdentity, not the order the audits were added,
+	// so every node ranks the volunteers the same way. The lowest identity is the highest priority
 	a.PriorityMap = make(map[Identity]int)
+	a.PriorityToIdentityMap = make(map[int]Identity)
 	auds := a.GetAuds()
+	sort.Slice(auds, func(i, j int) bool { return auds[i].Less(auds[j]) })
 	for i, aud := range auds {
 		a.PriorityMap[aud] = len(auds) - i - 1
 		a.PriorityToIdentityMap[len(auds)-i-1] = aud
@@ -195,14 +207,32 @@ func (a *AuthSet) Add(id Identity, status int) int {
 	return index
 }
 
-func (a *AuthSet) Majority() int {
+// FedCount is the number of federated servers in the set
+func (a AuthSet) FedCount() int {
 	totalf := 0
 	for _, s := range a.StatusArray {
 		if s > 0 {
 			totalf++
 		}
 	}
-	return totalf/2 + 1
+	return totalf
+}
+
+func (a AuthSet) Majority() int {
+	return a.FedCount()/2 + 1
+}
+
+// HasQuorum is true if the signers include a majority of the current federated servers.
+// Signers that are audits or not in the set do not count.
+func (a AuthSet) HasQuorum(signers map[Identity]bool) bool {
+	count := 0
+	for id, signed := range signers {
+		index, ok := a.IdentityMap[id]
+		if signed && ok && a.StatusArray[index] > 0 {
+			count++
+		}
+	}
+	return count >= a.Majority()
 }
 
 func (a *AuthSet) IsLeader(id Identity) bool {
@@ -224,7 +254,7 @@ func (a *AuthSet) Hash() Hash {
 
 func (a *AuthSet) FedIDtoIndex(id Identity) int {
 	for i, f := range a.GetFeds() {
-		if bytes.Compare(f[:], id[:]) == 0 {
+		if CompareIdentity(f, id) == 0 {
 			return i
 		}
 	}
@@ -254,8 +284,27 @@ func NewIdentityFromInt(u int) Identity {
 	return i
 }
 
-func (a Identity) less(b Identity) bool {
-	return bytes.Compare(a[:], b[:]) < 0
+// CompareIdentity is the single total order on identities. Anything that needs to order
+// identities must use it so every component of a run agrees. Returns -1, 0, or 1
+func CompareIdentity(a, b Identity) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// Less orders identities by CompareIdentity
+func (a Identity) Less(b Identity) bool {
+	return CompareIdentity(a, b) < 0
+}
+
+// SortedIdentities returns the keys of a map keyed by Identity (votes, majority decisions,
+// iack signers, ...) in ascending byte order, so proofs can be walked the same way on every
+// node. It is the same order encoding/json uses for these maps, see Identity.MarshalText
+func SortedIdentities[V any](m map[Identity]V) []Identity {
+	ids := make([]Identity, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Less(ids[j]) })
+	return ids
 }
 
 func (i *Identity) String() string {
@@ -273,10 +322,29 @@ func (i *Identity) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText lets maps keyed by Identity serialize, encoding/json sorts the keys by this text
+// so the same map always gives the same bytes
+func (i Identity) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText reads the hex after "ID-" into the leading bytes of the identity. Short input
+// is padded with zeros, so "ID-0000000a" is NewIdentityFromInt(10). String always writes all 32 bytes
+func (i *Identity) UnmarshalText(data []byte) error {
+	var b []byte
+	n, err := fmt.Sscanf(string(data), "ID-%x", &b)
+	if err != nil || n != 1 || len(b) > len(i) {
+		return fmt.Errorf("bad identity %q: %d %v", data, n, err)
+	}
+	*i = Identity{}
+	copy(i[:], b)
+	return nil
+}
+
 func (i *Identity) ReadString(s string) {
-	n, err := fmt.Sscanf(s, "ID-%x", i)
-	if err != nil || n != 1 {
-		HandleErrorf("Identity.ReadString(%v) failed: %d %v", s, n, err)
+	err := i.UnmarshalText([]byte(s))
+	if err != nil {
+		HandleErrorf("Identity.ReadString(%v) failed: %v", s, err)
 	}
 }
 
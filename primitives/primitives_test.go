@@ -1,15 +1,19 @@
 package primitives_test
 
 import (
+	"bytes"
 	"fmt"
-	. "github.com/FactomProject/electiontesting/primitives"
+	"strings"
 	"testing"
+
+	"github.com/FactomProject/electiontesting/errorhandling"
+	. "github.com/FactomProject/electiontesting/primitives"
 )
 
 func TestIsLeader(t *testing.T) {
 	a := NewAuthSet()
-	audits := []Identity{0, 1, 2, 3, 4}
-	feds := []Identity{5, 6, 7, 8}
+	audits := []Identity{NewIdentityFromInt(0), NewIdentityFromInt(1), NewIdentityFromInt(2), NewIdentityFromInt(3), NewIdentityFromInt(4)}
+	feds := []Identity{NewIdentityFromInt(5), NewIdentityFromInt(6), NewIdentityFromInt(7), NewIdentityFromInt(8)}
 
 	for _, aud := range audits {
 		a.Add(aud, 0)
@@ -44,11 +48,21 @@ func TestAuthSetSort(t *testing.T) {
 
 func TestIdentityReadString(t *testing.T) {
 
+	// Short input fills the leading bytes, String writes all 32
 	var i Identity
 	s := "ID-89abcdef"
 	i.ReadString(s)
+	if i.String() != s+strings.Repeat("0", 56) {
+		t.Errorf("Identity.ReadString(\"%s\") = %s", s, i.String())
+	}
+	if i[0] != 0x89 || i[3] != 0xef || i[4] != 0 {
+		t.Errorf("Identity.ReadString(\"%s\") did not fill the leading bytes: %x", s, i)
+	}
+
+	s = "ID-" + strings.Repeat("0123456789abcdef", 4)
+	i.ReadString(s)
 	if i.String() != s {
-		t.Errorf("Identity.ReadString(\"%s\")", s)
+		t.Errorf("Identity.ReadString(\"%s\") = %s", s, i.String())
 	}
 }
 
@@ -100,3 +114,150 @@ func TestAuthorityStatusReadString(t *testing.T) {
 	}
 
 }
+
+func TestCompareIdentityOrder(t *testing.T) {
+	// The last byte orders these the other way, so only a full comparison sorts them correctly
+	var a, b, c Identity
+	a[0], a[31] = 1, 9
+	b[0], b[31] = 2, 5
+	c[0], c[31] = 3, 1
+
+	if CompareIdentity(a, b) != -1 || CompareIdentity(c, b) != 1 || CompareIdentity(a, a) != 0 {
+		t.Error("CompareIdentity returned the wrong order")
+	}
+
+	auth := NewAuthSet()
+	auth.Add(c, 1)
+	auth.Add(a, 1)
+	auth.Add(b, 1)
+	auth.Sort()
+
+	for i := 1; i < len(auth.IdentityList); i++ {
+		if CompareIdentity(auth.IdentityList[i-1], auth.IdentityList[i]) != -1 {
+			t.Errorf("AuthSet.Sort does not agree with CompareIdentity at index %d", i)
+		}
+	}
+	if auth.FedIDtoIndex(b) != 1 {
+		t.Errorf("Expected fed b at index 1, found %d", auth.FedIDtoIndex(b))
+	}
+}
+
+func TestVolunteerPriorityFollowsCompareIdentity(t *testing.T) {
+	// Audits added highest identity first, so insertion order would rank them backwards
+	auth := NewAuthSet()
+	auth.Add(NewIdentityFromInt(1), 1)
+	votes := make(map[Identity]bool)
+	for i := 13; i >= 10; i-- {
+		auth.Add(NewIdentityFromInt(i), 0)
+		votes[NewIdentityFromInt(i)] = true
+	}
+
+	// A vote from every audit, output in sorted order
+	sorted := SortedIdentities(votes)
+
+	for i, id := range sorted {
+		p := auth.GetVolunteerPriority(id)
+		if p != len(sorted)-1-i || auth.PriorityToIdentityMap[p] != id {
+			t.Errorf("Audit %d in sorted order has priority %d, expected %d", i, p, len(sorted)-1-i)
+		}
+	}
+	if auth.GetVolunteerPriority(NewIdentityFromInt(10)) <= auth.GetVolunteerPriority(NewIdentityFromInt(13)) {
+		t.Error("The lowest identity should be the highest priority volunteer")
+	}
+}
+
+func TestAuthSetRejectsDuplicateIdentity(t *testing.T) {
+	oldOutput := errorhandling.Output
+	defer func() { errorhandling.Output = oldOutput }()
+	buf := new(bytes.Buffer)
+	errorhandling.Output = buf
+
+	a := NewAuthSet()
+	a.Add(NewIdentityFromInt(1), 1)
+	a.Add(NewIdentityFromInt(2), 1)
+	a.Add(NewIdentityFromInt(3), 1)
+
+	if a.Add(NewIdentityFromInt(2), 1) != 1 {
+		t.Error("Duplicate identity should return the existing index")
+	}
+	if buf.Len() == 0 {
+		t.Error("Duplicate identity was not reported")
+	}
+	if len(a.IdentityList) != 3 || a.Majority() != 2 {
+		t.Errorf("Duplicate identity changed the set: %d identities, majority %d", len(a.IdentityList), a.Majority())
+	}
+}
+
+func TestAuthSetQuorum(t *testing.T) {
+	for _, c := range []struct{ feds, majority int }{{4, 3}, {5, 3}, {1, 1}, {0, 1}} {
+		a := NewAuthSet()
+		for i := 0; i < c.feds; i++ {
+			a.Add(NewIdentityFromInt(i), 1)
+		}
+		a.Add(NewIdentityFromInt(100), 0)
+		if a.FedCount() != c.feds || a.Majority() != c.majority {
+			t.Errorf("%d feds: FedCount %d, Majority %d, expected majority %d", c.feds, a.FedCount(), a.Majority(), c.majority)
+		}
+	}
+
+	a := NewAuthSet()
+	for i := 0; i < 5; i++ {
+		a.Add(NewIdentityFromInt(i), 1)
+	}
+	aud := NewIdentityFromInt(100)
+	a.Add(aud, 0)
+
+	signers := map[Identity]bool{NewIdentityFromInt(0): true, NewIdentityFromInt(1): true, NewIdentityFromInt(2): true}
+	if !a.HasQuorum(signers) {
+		t.Error("3 of 5 feds should be a quorum")
+	}
+
+	// Demote one of the signers, the audit and an unknown identity do not make up for it
+	a.StatusArray[a.IdentityMap[NewIdentityFromInt(2)]] = 0
+	signers[aud] = true
+	signers[NewIdentityFromInt(200)] = true
+	if a.HasQuorum(signers) {
+		t.Error("2 of 4 feds should not be a quorum")
+	}
+	signers[NewIdentityFromInt(3)] = true
+	if !a.HasQuorum(signers) {
+		t.Error("3 of 4 feds should be a quorum")
+	}
+}
+
+func TestSortedIdentities(t *testing.T) {
+	// Ascending by byte value, the first byte is the most significant
+	proof := make(map[Identity]string)
+	var want []Identity
+	for i := 0; i < 10; i++ {
+		var id Identity
+		id[0], id[31] = byte(i), byte(10-i)
+		want = append(want, id)
+		proof[id] = fmt.Sprintf("vote %d", i)
+	}
+
+	for run := 0; run < 5; run++ {
+		got := SortedIdentities(proof)
+		if len(got) != len(want) {
+			t.Fatalf("Expected %d identities, found %d", len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Run %d: identity %d out of order", run, i)
+			}
+		}
+	}
+
+	if !want[0].Less(want[1]) || want[1].Less(want[0]) || want[0].Less(want[0]) {
+		t.Error("Less does not agree with CompareIdentity")
+	}
+
+	// The text form orders the same way, so json serializes proof maps in this order
+	for i := 1; i < len(want); i++ {
+		a, _ := want[i-1].MarshalText()
+		b, _ := want[i].MarshalText()
+		if string(a) >= string(b) {
+			t.Errorf("MarshalText does not order identities %d and %d like CompareIdentity", i-1, i)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package testhelper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/electiontesting/imessage"
+	. "github.com/FactomProject/electiontesting/messages"
+	. "github.com/FactomProject/electiontesting/primitives"
+)
+
+// AssertEmits fails the test if got and want do not hold the same messages. Messages are
+// compared by (type, signer, volunteer) and the order does not matter.
+func AssertEmits(t *testing.T, got []imessage.IMessage, want ...imessage.IMessage) {
+	t.Helper()
+	if diff := EmitsDiff(got, want...); diff != "" {
+		t.Errorf("Emitted messages do not match:\n%s", diff)
+	}
+}
+
+// EmitsDiff returns a readable list of the missing and unexpected messages, or ""
+// if got and want match the way AssertEmits compares them
+func EmitsDiff(got []imessage.IMessage, want ...imessage.IMessage) string {
+	count := make(map[string]int)
+	for _, m := range got {
+		count[emitKey(m)]++
+	}
+	for _, m := range want {
+		count[emitKey(m)]--
+	}
+
+	var lines []string
+	for k, c := range count {
+		for ; c > 0; c-- {
+			lines = append(lines, "unexpected: "+k)
+		}
+		for ; c < 0; c++ {
+			lines = append(lines, "missing:    "+k)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// emitKey returns the (type, signer, volunteer) of a message as a string.
+// IAcks have many signers, so the insisting server is used as their signer
+func emitKey(msg imessage.IMessage) string {
+	var signer, vol Identity
+	switch m := msg.(type) {
+	case *VolunteerMessage:
+		signer, vol = m.Signer, m.Signer
+	case *VoteMessage:
+		signer, vol = m.Signer, m.Volunteer.Signer
+	case *LeaderLevelMessage:
+		signer, vol = m.Signer, m.VolunteerMessage.Signer
+	case *MajorityDecisionMessage:
+		signer, vol = m.Signer, m.Volunteer.Signer
+	case *InsistMessage:
+		signer, vol = m.Signer, insistVolunteer(m)
+	case *IAckMessage:
+		signer, vol = m.Insist.Signer, insistVolunteer(&m.Insist)
+	case *PublishMessage:
+		signer, vol = m.Signer, insistVolunteer(&m.Insist)
+	}
+
+	// Drop the package path like the String() functions do
+	t := fmt.Sprintf("%T", msg)
+	t = t[strings.LastIndex(t, ".")+1:]
+	return fmt.Sprintf("%s signer=%s volunteer=%s", t, signer.String(), vol.String())
+}
+
+// insistVolunteer returns the volunteer of the majority decision with the lowest signer, so
+// an insist that mixes volunteers gets the same key every time
+func insistVolunteer(i *InsistMessage) Identity {
+	ids := SortedIdentities(i.MajorityMajorityDecisions)
+	if len(ids) == 0 {
+		return Identity{}
+	}
+	return i.MajorityMajorityDecisions[ids[0]].Volunteer.Signer
+}
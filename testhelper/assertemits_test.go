@@ -0,0 +1,56 @@
+package testhelper_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/electiontesting/imessage"
+	"github.com/FactomProject/electiontesting/messages"
+	"github.com/FactomProject/electiontesting/primitives"
+	. "github.com/FactomProject/electiontesting/testhelper"
+)
+
+func TestAssertEmits(t *testing.T) {
+	ah := NewAuthSetHelper(3, 2)
+	var loc primitives.ProcessListLocation
+	vol := messages.NewVolunteerMessage(messages.NewEomMessage(ah.GetAuds()[0], loc), ah.GetAuds()[0])
+	vf := ah.NewVoteFactory(vol)
+	v1, v2 := vf.NextVote(), vf.NextVote()
+
+	// Order does not matter
+	AssertEmits(t, imessage.MakeMessageArray(&v1, &vol, &v2), &v2, &v1, &vol)
+
+	// A missing message and an unexpected one are both reported
+	other := messages.NewVolunteerMessage(messages.NewEomMessage(ah.GetAuds()[1], loc), ah.GetAuds()[1])
+	diff := EmitsDiff(imessage.MakeMessageArray(&v1, &vol), &v1, &other)
+	if !strings.Contains(diff, "missing:    VolunteerMessage") || !strings.Contains(diff, "unexpected: VolunteerMessage") {
+		t.Errorf("Expected a missing and an unexpected volunteer, found:\n%s", diff)
+	}
+	if strings.Contains(diff, "VoteMessage") {
+		t.Errorf("Matching vote should not be in the diff:\n%s", diff)
+	}
+}
+
+func TestEmitsDiffMixedInsist(t *testing.T) {
+	ah := NewAuthSetHelper(3, 2)
+	var loc primitives.ProcessListLocation
+	feds, auds := ah.GetFeds(), ah.GetAuds()
+
+	// Majority decisions for two different volunteers in one insist
+	mds := make(map[primitives.Identity]messages.MajorityDecisionMessage)
+	for i, f := range feds {
+		a := auds[i%len(auds)]
+		vol := messages.NewVolunteerMessage(messages.NewEomMessage(a, loc), a)
+		mds[f] = messages.NewMajorityDecisionMessage(vol, nil, f)
+	}
+	insist := messages.NewInsistenceMessage(mds, feds[0])
+
+	// The lowest signer, feds[0], decided for auds[0]
+	want := "InsistMessage signer=" + feds[0].String() + " volunteer=" + auds[0].String()
+	for i := 0; i < 20; i++ {
+		diff := EmitsDiff(imessage.MakeMessageArray(&insist))
+		if diff != "unexpected: "+want {
+			t.Fatalf("Expected the key of the lowest signer's volunteer, found:\n%s", diff)
+		}
+	}
+}
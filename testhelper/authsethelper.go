@@ -98,8 +98,11 @@ func (v *VoteFactory) VotesListWithMajority() []VoteMessage {
 }
 
 func (v *VoteFactory) NextMajorityDecision() MajorityDecisionMessage {
-	return MajorityDecisionMessage{}
-	//return NewMajorityDecisionMessage(v.Volunteer, v.VotesMapWithMajority(), v.FedList[v.index])
+	votes := make(map[Identity]SignedMessage)
+	for k, vote := range v.VotesMapWithMajority() {
+		votes[k] = vote.SignedMessage
+	}
+	return NewMajorityDecisionMessage(v.Volunteer, votes, v.FedList[v.index])
 }
 
 func (v *VoteFactory) MajorityDecisionMapWithMajority() map[Identity]MajorityDecisionMessage {
@@ -0,0 +1,120 @@
+package testhelper
+
+import (
+	"github.com/FactomProject/electiontesting/imessage"
+	. "github.com/FactomProject/electiontesting/primitives"
+)
+
+// Minimize uses delta debugging to find a small subsequence of log for which fails still
+// returns true. Messages keep their relative order. If fails does not hold for the full log
+// then the log is returned untouched.
+func Minimize(log []imessage.IMessage, fails func([]imessage.IMessage) bool) []imessage.IMessage {
+	if !fails(log) {
+		return log
+	}
+
+	current := log
+	n := 2
+	for len(current) >= 2 {
+		chunks := splitMessages(current, n)
+		reduced := false
+
+		// See if any chunk on its own still fails
+		for _, c := range chunks {
+			if fails(c) {
+				current = c
+				n = 2
+				reduced = true
+				break
+			}
+		}
+
+		// See if we can drop any chunk and still fail
+		if !reduced && n > 2 {
+			for i := range chunks {
+				c := removeChunk(chunks, i)
+				if fails(c) {
+					current = c
+					n--
+					reduced = true
+					break
+				}
+			}
+		}
+
+		if !reduced {
+			if n >= len(current) {
+				// Every single message is needed
+				break
+			}
+			n *= 2
+			if n > len(current) {
+				n = len(current)
+			}
+		}
+	}
+
+	return current
+}
+
+// splitMessages splits the list into n chunks of (almost) equal size
+func splitMessages(list []imessage.IMessage, n int) [][]imessage.IMessage {
+	chunks := make([][]imessage.IMessage, 0, n)
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + (len(list)-start)/(n-i)
+		chunk := make([]imessage.IMessage, end-start)
+		copy(chunk, list[start:end])
+		chunks = append(chunks, chunk)
+		start = end
+	}
+	return chunks
+}
+
+// removeChunk returns all the chunks but the one at index, joined in order
+func removeChunk(chunks [][]imessage.IMessage, index int) []imessage.IMessage {
+	var list []imessage.IMessage
+	for i, c := range chunks {
+		if i != index {
+			list = append(list, c...)
+		}
+	}
+	return list
+}
+
+// MinimizeAuthSet shrinks the authority set one identity at a time, feds and audits alike,
+// for as long as fails still returns true. The log is passed to fails unchanged, it is up
+// to fails to ignore messages from identities that are no longer in the set.
+func MinimizeAuthSet(base AuthSet, log []imessage.IMessage, fails func(AuthSet, []imessage.IMessage) bool) AuthSet {
+	if !fails(base, log) {
+		return base
+	}
+
+	current := base
+	for {
+		reduced := false
+		// Try the last identities first, so the lowest ones are kept
+		for i := len(current.IdentityList) - 1; i >= 0; i-- {
+			smaller := removeIdentity(current, i)
+			if fails(smaller, log) {
+				current = smaller
+				reduced = true
+				break
+			}
+		}
+		if !reduced {
+			return current
+		}
+	}
+}
+
+// removeIdentity returns a new authority set with all but the identity at index
+func removeIdentity(a AuthSet, index int) AuthSet {
+	b := NewAuthSet()
+	for i, id := range a.IdentityList {
+		if i != index {
+			b.Add(id, a.StatusArray[i])
+		}
+	}
+	return *b
+}
@@ -0,0 +1,76 @@
+package testhelper_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/electiontesting/imessage"
+	"github.com/FactomProject/electiontesting/messages"
+	"github.com/FactomProject/electiontesting/primitives"
+	. "github.com/FactomProject/electiontesting/testhelper"
+)
+
+func TestMinimize(t *testing.T) {
+	ah := NewAuthSetHelper(20, 1)
+	var loc primitives.ProcessListLocation
+	vol := messages.NewVolunteerMessage(messages.NewEomMessage(ah.GetAuds()[0], loc), ah.GetAuds()[0])
+	vf := ah.NewVoteFactory(vol)
+
+	var log []imessage.IMessage
+	for i := 0; i < 20; i++ {
+		vote := vf.NextVote()
+		log = append(log, &vote)
+	}
+
+	// Only the votes from these 3 feds are needed to trigger the condition
+	feds := ah.GetFeds()
+	needed := []primitives.Identity{feds[3], feds[11], feds[17]}
+	fails := func(l []imessage.IMessage) bool {
+		found := 0
+		for _, n := range needed {
+			for _, m := range l {
+				if m.(*messages.VoteMessage).Signer == n {
+					found++
+					break
+				}
+			}
+		}
+		return found == len(needed)
+	}
+
+	minimal := Minimize(log, fails)
+	if len(minimal) != 3 {
+		t.Fatalf("Expected a minimized log of 3 messages, got %d", len(minimal))
+	}
+	for i, m := range minimal {
+		if m.(*messages.VoteMessage).Signer != needed[i] {
+			t.Errorf("Message %d of the minimized log is not the expected vote", i)
+		}
+	}
+
+	// A log that does not fail is not touched
+	if len(Minimize(log, func([]imessage.IMessage) bool { return false })) != len(log) {
+		t.Error("Log that does not fail should not be minimized")
+	}
+}
+
+func TestMinimizeAuthSet(t *testing.T) {
+	ah := NewAuthSetHelper(7, 3)
+
+	// The bug only shows up with at least 4 feds
+	fails := func(a primitives.AuthSet, _ []imessage.IMessage) bool {
+		return len(a.GetFeds()) >= 4
+	}
+
+	minimal := MinimizeAuthSet(ah.GetAuthSet(), nil, fails)
+	if len(minimal.GetFeds()) != 4 {
+		t.Errorf("Expected minimization to stop at 4 feds, found %d", len(minimal.GetFeds()))
+	}
+	if len(minimal.GetAuds()) != 0 {
+		t.Errorf("Audits are not needed for the bug, found %d", len(minimal.GetAuds()))
+	}
+	for i, f := range minimal.GetFeds() {
+		if f != ah.GetFeds()[i] {
+			t.Errorf("Expected the lowest feds to be kept, fed %d differs", i)
+		}
+	}
+}